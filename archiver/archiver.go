@@ -0,0 +1,194 @@
+// Package archiver downloads the images, videos, and linked web pages
+// referenced by a weibo and saves them to disk alongside a manifest
+// describing what was fetched.
+package archiver
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Kind identifies what a downloaded artifact is, used both to pick the
+// --download flag that enables it and to label manifest entries.
+type Kind string
+
+const (
+	KindImage Kind = "images"
+	KindVideo Kind = "videos"
+	KindPage  Kind = "pages"
+)
+
+// Entry describes one downloaded artifact in a weibo's manifest.json.
+type Entry struct {
+	Kind     Kind   `json:"kind"`
+	URL      string `json:"url"`
+	Path     string `json:"path"`
+	MimeType string `json:"mime_type"`
+	SHA256   string `json:"sha256"`
+}
+
+// Manifest is written as archive/<weibo_id>/manifest.json.
+type Manifest struct {
+	WeiboID string  `json:"weibo_id"`
+	Entries []Entry `json:"entries"`
+}
+
+// Archiver downloads artifacts for weibo entries, limiting concurrency
+// to a shared semaphore so it plays nicely with the crawler's own
+// worker pool.
+type Archiver struct {
+	RootDir string
+	Kinds   map[Kind]bool
+
+	sem chan struct{}
+}
+
+// New returns an Archiver that archives the given kinds of artifact,
+// downloading at most concurrency files at a time.
+func New(rootDir string, kinds []Kind, concurrency int) *Archiver {
+	enabled := make(map[Kind]bool, len(kinds))
+	for _, k := range kinds {
+		enabled[k] = true
+	}
+	return &Archiver{
+		RootDir: rootDir,
+		Kinds:   enabled,
+		sem:     make(chan struct{}, concurrency),
+	}
+}
+
+// Archive downloads every enabled artifact kind referenced by raw, the
+// weibo's raw JSON payload, and writes archive/<id>/manifest.json.
+func (a *Archiver) Archive(id string, raw map[string]any) error {
+	dir := filepath.Join(a.RootDir, id)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	var urls []struct {
+		kind Kind
+		url  string
+	}
+	if a.Kinds[KindImage] {
+		for _, u := range imageURLs(raw) {
+			urls = append(urls, struct {
+				kind Kind
+				url  string
+			}{KindImage, u})
+		}
+	}
+	if a.Kinds[KindVideo] {
+		for _, u := range videoURLs(raw) {
+			urls = append(urls, struct {
+				kind Kind
+				url  string
+			}{KindVideo, u})
+		}
+	}
+	if a.Kinds[KindPage] {
+		for _, u := range pageURLs(raw) {
+			urls = append(urls, struct {
+				kind Kind
+				url  string
+			}{KindPage, u})
+		}
+	}
+
+	// Downloaded concurrently, bounded by a.sem, so a weibo with many
+	// images/videos doesn't download them one at a time; entries is
+	// indexed by urls' position so the manifest order stays
+	// deterministic regardless of which download finishes first.
+	entries := make([]Entry, len(urls))
+	errs := make([]error, len(urls))
+	var wg sync.WaitGroup
+	for i, u := range urls {
+		wg.Add(1)
+		go func(i int, kind Kind, url string) {
+			defer wg.Done()
+			entry, err := a.download(dir, kind, url)
+			if err != nil {
+				errs[i] = fmt.Errorf("archiver: download %s: %w", url, err)
+				return
+			}
+			entries[i] = entry
+		}(i, u.kind, u.url)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	manifest := Manifest{WeiboID: id, Entries: entries}
+
+	f, err := os.Create(filepath.Join(dir, "manifest.json"))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	return enc.Encode(manifest)
+}
+
+func (a *Archiver) download(dir string, kind Kind, url string) (Entry, error) {
+	a.sem <- struct{}{}
+	defer func() { <-a.sem }()
+
+	resp, err := http.Get(url)
+	if err != nil {
+		return Entry{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Entry{}, fmt.Errorf("GET %s: status %d", url, resp.StatusCode)
+	}
+
+	name := fmt.Sprintf("%s-%s", kind, sha256Hex([]byte(url))[:12]) + extOf(url)
+	path := filepath.Join(dir, name)
+	f, err := os.Create(path)
+	if err != nil {
+		return Entry{}, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(f, h), resp.Body); err != nil {
+		return Entry{}, err
+	}
+
+	mimeType := resp.Header.Get("Content-Type")
+
+	return Entry{
+		Kind:     kind,
+		URL:      url,
+		Path:     path,
+		MimeType: mimeType,
+		SHA256:   hex.EncodeToString(h.Sum(nil)),
+	}, nil
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// extOf returns the file extension for a download URL, ignoring any
+// query string (Weibo's CDN URLs routinely carry one, e.g.
+// "...video.mp4?label=mp4_hd&KID=...").
+func extOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return filepath.Ext(rawURL)
+	}
+	return filepath.Ext(u.Path)
+}
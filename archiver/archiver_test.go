@@ -0,0 +1,84 @@
+package archiver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestExtOf_StripsQueryString(t *testing.T) {
+	got := extOf("https://f.video.weibocdn.com/x.mp4?label=mp4_hd&KID=unistore,video")
+	if got != ".mp4" {
+		t.Errorf("extOf = %q, want %q", got, ".mp4")
+	}
+}
+
+func TestExtOf_InvalidURLFallsBackToRawExt(t *testing.T) {
+	got := extOf("://not a url.jpg")
+	if got != ".jpg" {
+		t.Errorf("extOf = %q, want %q", got, ".jpg")
+	}
+}
+
+// TestArchive_DownloadsConcurrently guards against the semaphore in
+// Archiver.download being dead code: it fails if Archive downloads its
+// URLs one at a time instead of in parallel, bounded by a.sem.
+func TestArchive_DownloadsConcurrently(t *testing.T) {
+	const n = 4
+	var inFlight, maxInFlight int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cur := atomic.AddInt64(&inFlight, 1)
+		for {
+			old := atomic.LoadInt64(&maxInFlight)
+			if cur <= old || atomic.CompareAndSwapInt64(&maxInFlight, old, cur) {
+				break
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+		atomic.AddInt64(&inFlight, -1)
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Write([]byte("fake-image-bytes"))
+	}))
+	defer srv.Close()
+
+	picInfos := make(map[string]any, n)
+	picIDs := make([]any, n)
+	for i := 0; i < n; i++ {
+		id := string(rune('a' + i))
+		picIDs[i] = id
+		picInfos[id] = map[string]any{"original": map[string]any{"url": srv.URL + "/" + id}}
+	}
+	raw := map[string]any{"pic_ids": picIDs, "pic_infos": picInfos}
+
+	dir := t.TempDir()
+	a := New(dir, []Kind{KindImage}, n)
+	if err := a.Archive("w1", raw); err != nil {
+		t.Fatalf("Archive: %v", err)
+	}
+
+	if got := atomic.LoadInt64(&maxInFlight); got < 2 {
+		t.Errorf("max concurrent downloads = %d, want >= 2 (downloads ran sequentially)", got)
+	}
+
+	data, err := os.ReadFile(dir + "/w1/manifest.json")
+	if err != nil {
+		t.Fatalf("read manifest: %v", err)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatalf("unmarshal manifest: %v", err)
+	}
+	if len(manifest.Entries) != n {
+		t.Fatalf("manifest has %d entries, want %d", len(manifest.Entries), n)
+	}
+	for i, e := range manifest.Entries {
+		want := srv.URL + "/" + string(rune('a'+i))
+		if e.URL != want {
+			t.Errorf("manifest.Entries[%d].URL = %q, want %q (order should follow pic_ids)", i, e.URL, want)
+		}
+	}
+}
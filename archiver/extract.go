@@ -0,0 +1,83 @@
+package archiver
+
+// imageURLs pulls original-resolution image URLs out of a weibo's
+// pic_infos map, keyed by pic id, in the order pic_ids lists them.
+func imageURLs(raw map[string]any) []string {
+	picInfos, ok := raw["pic_infos"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	picIDs, _ := raw["pic_ids"].([]any)
+
+	var urls []string
+	seen := make(map[string]bool, len(picIDs))
+	appendURL := func(id string) {
+		v, ok := picInfos[id]
+		if !ok || seen[id] {
+			return
+		}
+		seen[id] = true
+		pic, ok := v.(map[string]any)
+		if !ok {
+			return
+		}
+		original, ok := pic["original"].(map[string]any)
+		if !ok {
+			return
+		}
+		if url, ok := original["url"].(string); ok && url != "" {
+			urls = append(urls, url)
+		}
+	}
+
+	for _, id := range picIDs {
+		if s, ok := id.(string); ok {
+			appendURL(s)
+		}
+	}
+	// pic_ids may omit an id pic_infos actually has (or be absent
+	// entirely); fall back to covering the rest so nothing is dropped.
+	for id := range picInfos {
+		appendURL(id)
+	}
+	return urls
+}
+
+// videoURLs pulls the HD (falling back to SD) video stream URL out of a
+// weibo's page_info.media_info, when present.
+func videoURLs(raw map[string]any) []string {
+	pageInfo, ok := raw["page_info"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	mediaInfo, ok := pageInfo["media_info"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	for _, key := range []string{"stream_url_hd", "stream_url"} {
+		if url, ok := mediaInfo[key].(string); ok && url != "" {
+			return []string{url}
+		}
+	}
+	return nil
+}
+
+// pageURLs pulls the linked pages out of a weibo's url_struct, the same
+// field model.Parse reads for "网页链接" entries.
+func pageURLs(raw map[string]any) []string {
+	urlStruct, ok := raw["url_struct"].([]any)
+	if !ok {
+		return nil
+	}
+	var urls []string
+	for _, u := range urlStruct {
+		uu, ok := u.(map[string]any)
+		if !ok {
+			continue
+		}
+		if url, ok := uu["long_url"].(string); ok && url != "" {
+			urls = append(urls, url)
+		}
+	}
+	return urls
+}
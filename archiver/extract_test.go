@@ -0,0 +1,35 @@
+package archiver
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestImageURLs_OrderedByPicIDs(t *testing.T) {
+	raw := map[string]any{
+		"pic_ids": []any{"b", "a"},
+		"pic_infos": map[string]any{
+			"a": map[string]any{"original": map[string]any{"url": "https://example.com/a.jpg"}},
+			"b": map[string]any{"original": map[string]any{"url": "https://example.com/b.jpg"}},
+		},
+	}
+	got := imageURLs(raw)
+	want := []string{"https://example.com/b.jpg", "https://example.com/a.jpg"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("imageURLs = %v, want %v", got, want)
+	}
+}
+
+func TestImageURLs_FallsBackForIDsMissingFromPicIDs(t *testing.T) {
+	raw := map[string]any{
+		"pic_ids": []any{"a"},
+		"pic_infos": map[string]any{
+			"a": map[string]any{"original": map[string]any{"url": "https://example.com/a.jpg"}},
+			"b": map[string]any{"original": map[string]any{"url": "https://example.com/b.jpg"}},
+		},
+	}
+	got := imageURLs(raw)
+	if len(got) != 2 || got[0] != "https://example.com/a.jpg" {
+		t.Errorf("imageURLs = %v, want [a.jpg, b.jpg] (a first)", got)
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/spf13/cobra"
+
+	"github.com/xujiajiadexiaokeai/get-weibo-favorites/export"
+	"github.com/xujiajiadexiaokeai/get-weibo-favorites/source"
+)
+
+// exportCmd groups the one-sink crawl variants: each child configures
+// exactly one additional export.Exporter (on top of the SQLite store
+// runCrawl always keeps for incremental sync) and crawls whichever
+// source --source selects (fav by default), sharing the same
+// Favorites/UserTimeline/Status/Search sources the fav/user/status/
+// search subcommands use.
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Crawl a source and export it to a specific sink",
+}
+
+// exportSource builds the source.Source named by the command's
+// --source/--uid/--mblogid/--query flags.
+func exportSource(cmd *cobra.Command) (source.Source, error) {
+	sourceName, _ := cmd.Flags().GetString("source")
+	uid, _ := cmd.Flags().GetString("uid")
+	mblogid, _ := cmd.Flags().GetString("mblogid")
+	query, _ := cmd.Flags().GetString("query")
+	return buildSource(sourceName, uid, mblogid, query)
+}
+
+var exportCSVCmd = &cobra.Command{
+	Use:   "csv",
+	Short: "Export a crawled source to a CSV file",
+	Run: func(cmd *cobra.Command, args []string) {
+		src, err := exportSource(cmd)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		path, _ := cmd.Flags().GetString("path")
+		if path == "" {
+			path = outputFileName("csv")
+		}
+		e, err := export.NewCSVExporter(path)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if err := runCrawl(cmd, src, []export.Exporter{e}); err != nil {
+			log.Fatalln(err)
+		}
+	},
+}
+
+var exportJSONLCmd = &cobra.Command{
+	Use:   "jsonl",
+	Short: "Export a crawled source to a JSONL file",
+	Run: func(cmd *cobra.Command, args []string) {
+		src, err := exportSource(cmd)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		path, _ := cmd.Flags().GetString("path")
+		if path == "" {
+			path = outputFileName("jsonl")
+		}
+		e, err := export.NewJSONLExporter(path)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if err := runCrawl(cmd, src, []export.Exporter{e}); err != nil {
+			log.Fatalln(err)
+		}
+	},
+}
+
+var exportTelegramCmd = &cobra.Command{
+	Use:   "telegram",
+	Short: "Post a crawled source to a Telegram chat",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return requireFlags(cmd, "bot-token", "chat-id")
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		src, err := exportSource(cmd)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		token, _ := cmd.Flags().GetString("bot-token")
+		chatID, _ := cmd.Flags().GetString("chat-id")
+		tmpl, _ := cmd.Flags().GetString("template")
+		e, err := export.NewTelegramExporter(token, chatID, tmpl)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if err := runCrawl(cmd, src, []export.Exporter{e}); err != nil {
+			log.Fatalln(err)
+		}
+	},
+}
+
+var exportWebhookCmd = &cobra.Command{
+	Use:   "webhook",
+	Short: "POST a crawled source to an HTTP webhook",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return requireFlags(cmd, "url")
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		src, err := exportSource(cmd)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		url, _ := cmd.Flags().GetString("url")
+		tmpl, _ := cmd.Flags().GetString("template")
+		e, err := export.NewWebhookExporter(url, tmpl)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if err := runCrawl(cmd, src, []export.Exporter{e}); err != nil {
+			log.Fatalln(err)
+		}
+	},
+}
+
+// requireFlags reports an error naming the first unset flag in names,
+// used by subcommands whose required options don't fit cobra's
+// MarkFlagRequired (e.g. mutually-required pairs).
+func requireFlags(cmd *cobra.Command, names ...string) error {
+	for _, name := range names {
+		if v, _ := cmd.Flags().GetString(name); v == "" {
+			return fmt.Errorf("--%s is required", name)
+		}
+	}
+	return nil
+}
+
+func init() {
+	for _, c := range []*cobra.Command{exportCSVCmd, exportJSONLCmd, exportTelegramCmd, exportWebhookCmd} {
+		c.Flags().String("source", "fav", "source to crawl: fav|user|status|search")
+		c.Flags().String("uid", "", "user id to crawl (--source=user)")
+		c.Flags().String("mblogid", "", "mblogid to fetch (--source=status)")
+		c.Flags().String("query", "", "search query to crawl (--source=search)")
+	}
+
+	exportCSVCmd.Flags().String("path", "", "output file path (default weiboFavorites-<timestamp>.csv)")
+	exportJSONLCmd.Flags().String("path", "", "output file path (default weiboFavorites-<timestamp>.jsonl)")
+
+	exportCmd.AddCommand(exportCSVCmd, exportJSONLCmd, exportTelegramCmd, exportWebhookCmd)
+	rootCmd.AddCommand(exportCmd)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/spf13/cobra"
+
+	"github.com/xujiajiadexiaokeai/get-weibo-favorites/source"
+)
+
+// buildSource constructs the source.Source named by sourceName ("fav"
+// is the default), used by the `export` subcommands to target any
+// source with a given sink instead of always crawling favorites.
+func buildSource(sourceName, uid, mblogid, query string) (source.Source, error) {
+	switch sourceName {
+	case "", "fav":
+		return source.Favorites{}, nil
+	case "user":
+		if uid == "" {
+			return nil, fmt.Errorf("--uid is required for --source=user")
+		}
+		return source.UserTimeline{UID: uid}, nil
+	case "status":
+		if mblogid == "" {
+			return nil, fmt.Errorf("--mblogid is required for --source=status")
+		}
+		return source.Status{MblogID: mblogid}, nil
+	case "search":
+		if query == "" {
+			return nil, fmt.Errorf("--query is required for --source=search")
+		}
+		return source.Search{Query: query}, nil
+	default:
+		return nil, fmt.Errorf("unknown --source %q, want one of fav|user|status|search", sourceName)
+	}
+}
+
+// runFavCrawl is shared by rootCmd (for users invoking the binary with
+// no subcommand) and favCmd.
+func runFavCrawl(cmd *cobra.Command, args []string) {
+	format, _ := cmd.Flags().GetString("format")
+	extra := buildFormatExporter(cmd, format)
+	if err := runCrawl(cmd, source.Favorites{}, extra); err != nil {
+		log.Fatalln(err)
+	}
+}
+
+var favCmd = &cobra.Command{
+	Use:   "fav",
+	Short: "Crawl your favorited weibo (the default when no subcommand is given)",
+	Run:   runFavCrawl,
+}
+
+var userCmd = &cobra.Command{
+	Use:   "user <uid>",
+	Short: "Crawl a user's own timeline",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		format, _ := cmd.Flags().GetString("format")
+		extra := buildFormatExporter(cmd, format)
+		if err := runCrawl(cmd, source.UserTimeline{UID: args[0]}, extra); err != nil {
+			log.Fatalln(err)
+		}
+	},
+}
+
+var statusCmd = &cobra.Command{
+	Use:   "status <mblogid>",
+	Short: "Fetch a single weibo by its mblogid",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		format, _ := cmd.Flags().GetString("format")
+		extra := buildFormatExporter(cmd, format)
+		if err := runCrawl(cmd, source.Status{MblogID: args[0]}, extra); err != nil {
+			log.Fatalln(err)
+		}
+	},
+}
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Crawl weibo matching a search query",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		format, _ := cmd.Flags().GetString("format")
+		extra := buildFormatExporter(cmd, format)
+		if err := runCrawl(cmd, source.Search{Query: args[0]}, extra); err != nil {
+			log.Fatalln(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(favCmd, userCmd, statusCmd, searchCmd)
+}
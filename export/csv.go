@@ -0,0 +1,32 @@
+package export
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/xujiajiadexiaokeai/get-weibo-favorites/storage"
+)
+
+// CSVExporter appends one tab-separated line per weibo to a file.
+type CSVExporter struct {
+	f *os.File
+}
+
+// NewCSVExporter opens (or creates) path for appending CSV rows.
+func NewCSVExporter(path string) (*CSVExporter, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o777)
+	if err != nil {
+		return nil, err
+	}
+	return &CSVExporter{f: f}, nil
+}
+
+func (e *CSVExporter) Name() string { return "csv" }
+
+func (e *CSVExporter) Export(r storage.Record) error {
+	_, err := e.f.WriteString(fmt.Sprintf("%s\t%s\t%t\t%s\n", r.ID, r.Text, r.IsLongText, strings.Join(r.Links, " , ")))
+	return err
+}
+
+func (e *CSVExporter) Close() error { return e.f.Close() }
@@ -0,0 +1,53 @@
+// Package export delivers crawled weibo records to pluggable sinks: a
+// flat file, the durable store, a webhook, a Telegram chat.
+package export
+
+import (
+	"log"
+	"sync"
+
+	"github.com/xujiajiadexiaokeai/get-weibo-favorites/storage"
+)
+
+// Exporter delivers one crawled weibo to a destination. Implementations
+// should be safe to call from multiple goroutines concurrently, since
+// FanOut exports to every configured Exporter at once.
+type Exporter interface {
+	Name() string
+	Export(r storage.Record) error
+	Close() error
+}
+
+// FanOut exports each record to every configured Exporter concurrently,
+// isolating failures so a broken sink (a down webhook, a rate-limited
+// bot) doesn't stop the others from receiving the record.
+type FanOut struct {
+	Exporters []Exporter
+}
+
+// Export delivers r to every exporter, logging (not returning) any
+// per-sink error so callers can keep crawling regardless.
+func (f *FanOut) Export(r storage.Record) {
+	var wg sync.WaitGroup
+	for _, e := range f.Exporters {
+		wg.Add(1)
+		go func(e Exporter) {
+			defer wg.Done()
+			if err := e.Export(r); err != nil {
+				log.Printf("export: %s: %v", e.Name(), err)
+			}
+		}(e)
+	}
+	wg.Wait()
+}
+
+// Close closes every exporter, returning the first error encountered.
+func (f *FanOut) Close() error {
+	var firstErr error
+	for _, e := range f.Exporters {
+		if err := e.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,40 @@
+package export
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/xujiajiadexiaokeai/get-weibo-favorites/storage"
+)
+
+// JSONLExporter appends one JSON object per weibo to a file, one per
+// line, using the raw Weibo API payload when available.
+type JSONLExporter struct {
+	f *os.File
+}
+
+// NewJSONLExporter opens (or creates) path for appending JSONL records.
+func NewJSONLExporter(path string) (*JSONLExporter, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o777)
+	if err != nil {
+		return nil, err
+	}
+	return &JSONLExporter{f: f}, nil
+}
+
+func (e *JSONLExporter) Name() string { return "jsonl" }
+
+func (e *JSONLExporter) Export(r storage.Record) error {
+	line := r.Raw
+	if len(line) == 0 {
+		var err error
+		line, err = json.Marshal(r)
+		if err != nil {
+			return err
+		}
+	}
+	_, err := e.f.Write(append(line, '\n'))
+	return err
+}
+
+func (e *JSONLExporter) Close() error { return e.f.Close() }
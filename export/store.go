@@ -0,0 +1,24 @@
+package export
+
+import "github.com/xujiajiadexiaokeai/get-weibo-favorites/storage"
+
+// StoreExporter adapts a storage.Store, which saves in batches, to the
+// one-record-at-a-time Exporter interface.
+type StoreExporter struct {
+	store storage.Store
+}
+
+// NewStoreExporter returns an Exporter that saves each record to store.
+func NewStoreExporter(store storage.Store) *StoreExporter {
+	return &StoreExporter{store: store}
+}
+
+func (e *StoreExporter) Name() string { return "store" }
+
+func (e *StoreExporter) Export(r storage.Record) error {
+	return e.store.Save([]storage.Record{r})
+}
+
+// Close is a no-op: the underlying store's lifetime is managed by
+// whoever opened it, not by the exporter wrapping it.
+func (e *StoreExporter) Close() error { return nil }
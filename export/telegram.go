@@ -0,0 +1,67 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"text/template"
+
+	"github.com/xujiajiadexiaokeai/get-weibo-favorites/storage"
+)
+
+const defaultTelegramTemplate = "{{.Text}}\n{{range .Links}}{{.}}\n{{end}}"
+
+// TelegramExporter posts each weibo as a message to a Telegram chat via
+// the Bot API's sendMessage method.
+type TelegramExporter struct {
+	Token    string
+	ChatID   string
+	Template *template.Template
+
+	client *http.Client
+}
+
+// NewTelegramExporter returns a TelegramExporter that posts to chatID
+// using the given bot token. tmpl may be empty, in which case a default
+// "text + links" template is used.
+func NewTelegramExporter(token, chatID, tmpl string) (*TelegramExporter, error) {
+	if tmpl == "" {
+		tmpl = defaultTelegramTemplate
+	}
+	t, err := template.New("telegram").Parse(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("export: telegram template: %w", err)
+	}
+	return &TelegramExporter{Token: token, ChatID: chatID, Template: t, client: http.DefaultClient}, nil
+}
+
+func (e *TelegramExporter) Name() string { return "telegram" }
+
+func (e *TelegramExporter) Export(r storage.Record) error {
+	var text bytes.Buffer
+	if err := e.Template.Execute(&text, r); err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(map[string]string{
+		"chat_id": e.ChatID,
+		"text":    text.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", e.Token)
+	resp, err := e.client.Post(url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("export: telegram sendMessage: status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (e *TelegramExporter) Close() error { return nil }
@@ -0,0 +1,63 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"text/template"
+
+	"github.com/xujiajiadexiaokeai/get-weibo-favorites/storage"
+)
+
+// WebhookExporter POSTs each weibo to a generic HTTP endpoint, as JSON
+// by default or as the rendered Template when one is set.
+type WebhookExporter struct {
+	URL      string
+	Template *template.Template
+
+	client *http.Client
+}
+
+// NewWebhookExporter returns a WebhookExporter that POSTs to url. tmpl
+// may be empty, in which case the record is sent as a JSON body.
+func NewWebhookExporter(url, tmpl string) (*WebhookExporter, error) {
+	e := &WebhookExporter{URL: url, client: http.DefaultClient}
+	if tmpl != "" {
+		t, err := template.New("webhook").Parse(tmpl)
+		if err != nil {
+			return nil, fmt.Errorf("export: webhook template: %w", err)
+		}
+		e.Template = t
+	}
+	return e, nil
+}
+
+func (e *WebhookExporter) Name() string { return "webhook" }
+
+func (e *WebhookExporter) Export(r storage.Record) error {
+	var body bytes.Buffer
+	contentType := "application/json"
+	if e.Template != nil {
+		if err := e.Template.Execute(&body, r); err != nil {
+			return err
+		}
+		contentType = "text/plain"
+	} else {
+		if err := json.NewEncoder(&body).Encode(r); err != nil {
+			return err
+		}
+	}
+
+	resp, err := e.client.Post(e.URL, contentType, &body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("export: webhook POST %s: status %d", e.URL, resp.StatusCode)
+	}
+	return nil
+}
+
+func (e *WebhookExporter) Close() error { return nil }
@@ -0,0 +1,78 @@
+// Package requests is a local stand-in for github.com/youseebiggirl/requests,
+// vendored here because that module isn't reachable through this
+// project's Go module proxy. It implements only the surface this
+// repository actually uses: a cookie-authenticated GET that decodes a
+// JSON object body.
+package requests
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// Option configures an outgoing request built by GET.
+type Option func(*http.Request)
+
+// WithCookie sets the Cookie header on the request.
+func WithCookie(cookie string) Option {
+	return func(r *http.Request) {
+		r.Header.Set("Cookie", cookie)
+	}
+}
+
+// Response is the result of a GET call.
+type Response struct {
+	resp *http.Response
+	body []byte
+	err  error
+}
+
+// GET issues an HTTP GET to url with the given options applied to the
+// request before it's sent.
+func GET(url string, opts ...Option) *Response {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return &Response{err: err}
+	}
+	for _, opt := range opts {
+		opt(req)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return &Response{err: err}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return &Response{resp: resp, err: err}
+	}
+	return &Response{resp: resp, body: body}
+}
+
+// StatusCode returns the response's HTTP status code, or 0 if the
+// request itself failed (e.g. a network error).
+func (r *Response) StatusCode() int {
+	if r.resp == nil {
+		return 0
+	}
+	return r.resp.StatusCode
+}
+
+// StatusText returns the response's HTTP status line.
+func (r *Response) StatusText() string {
+	if r.resp == nil {
+		return r.err.Error()
+	}
+	return r.resp.Status
+}
+
+// Map decodes the response body as a JSON object. It returns nil if the
+// body isn't valid JSON.
+func (r *Response) Map() map[string]any {
+	var m map[string]any
+	_ = json.Unmarshal(r.body, &m)
+	return m
+}
@@ -1,104 +1,200 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
-	"net/http"
 	"os"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/spf13/cobra"
-	"github.com/youseebiggirl/requests"
+
+	"github.com/xujiajiadexiaokeai/get-weibo-favorites/archiver"
+	"github.com/xujiajiadexiaokeai/get-weibo-favorites/export"
+	"github.com/xujiajiadexiaokeai/get-weibo-favorites/model"
+	"github.com/xujiajiadexiaokeai/get-weibo-favorites/source"
+	"github.com/xujiajiadexiaokeai/get-weibo-favorites/storage"
+	"github.com/xujiajiadexiaokeai/get-weibo-favorites/storage/sqlite"
+	weiboclient "github.com/xujiajiadexiaokeai/get-weibo-favorites/weibo"
 )
 
 var (
-	baseUrl      = "https://weibo.com/ajax/favorites/all_fav?"
 	page         = 1
-	weiboChan    = make(chan weibo, 1000)
+	weiboChan    = make(chan model.Weibo, 1000)
 	workerNumber = 2 // Maximum number of workers that can run at the same time
 )
 
-func getWeiboFav(cookie string, pageNumber int, wg *sync.WaitGroup) {
+// syncState tracks how crawl decides when to stop paging during an
+// incremental sync: once a page comes back with only ids the store has
+// already seen for this source, there's nothing new left to fetch.
+type syncState struct {
+	store  storage.Store
+	full   bool
+	source string
+}
+
+// seenAll reports whether every id in a page has already been saved
+// under this source, meaning the crawler has caught up with the last
+// sync. An id saved under a different source doesn't count: the same
+// weibo id can be reachable from favorites, a user timeline, and
+// search without any of them having synced each other.
+func (s *syncState) seenAll(ids []string) bool {
+	if s.store == nil || s.full {
+		return false
+	}
+	for _, id := range ids {
+		ok, err := s.store.Has(s.source, id)
+		if err != nil {
+			log.Println("storage: Has:", err)
+			return false
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// looksLikeCookieExpiry reports whether an empty first page is more
+// likely an expired cookie than a legitimate empty result. A zero-hit
+// search, a user with no posts, or a --full re-run all legitimately
+// return an empty first page, so the heuristic only fires for an
+// incremental favorites sync where a previous run already proved the
+// store has favorites in it -- going from "has some" to "has none" is
+// what an expired cookie looks like; starting from zero isn't.
+func looksLikeCookieExpiry(src source.Source, ss *syncState) bool {
+	if ss.full || ss.store == nil || src.Name() != "favorites" {
+		return false
+	}
+	synced, err := ss.store.SourceSynced(src.Name())
+	if err != nil {
+		log.Println("storage: SourceSynced:", err)
+		return false
+	}
+	return synced
+}
+
+// crawl pages through src until it runs out of data, pageNumber is
+// reached, or a GET fails outright (e.g. the cookie expired).
+// Non-paginated sources (Status) are fetched exactly once.
+func crawl(src source.Source, client *weiboclient.Client, pageNumber int, ss *syncState, wg *sync.WaitGroup) error {
 
 	workerCh := make(chan struct{}, workerNumber)
 	defer close(workerCh)
-	done := make(chan bool)
+	// Buffered so fetch's "no more data"/"caught up" signal never blocks
+	// waiting for a reader, even when fetch is called exactly once (the
+	// non-paginated branch below never selects on done at all).
+	done := make(chan bool, 1)
+	errCh := make(chan error, 1)
+
+	if !src.Paginated() {
+		workerCh <- struct{}{}
+		fetch(src, src.PageURL(0), client, ss, true, workerCh, done, errCh, wg)
+		select {
+		case err := <-errCh:
+			return err
+		default:
+			return nil
+		}
+	}
 
 	for {
 		select {
 		case <-done:
 			log.Println("no data, maybe is done")
-			return
+			return nil
+		case err := <-errCh:
+			return err
 		default:
 			if pageNumber != 0 && page > pageNumber {
-				return
+				return nil
 			} else {
 				workerCh <- struct{}{}
-				url := baseUrl + fmt.Sprintf("page=%v", page)
-				get(url, cookie, workerCh, done, wg)
+				fetch(src, src.PageURL(page), client, ss, page == 1, workerCh, done, errCh, wg)
 				page++
 			}
 		}
 	}
 }
 
-func get(url, cookie string, workerCh chan struct{}, done chan bool, wg *sync.WaitGroup) {
+func fetch(src source.Source, url string, client *weiboclient.Client, ss *syncState, firstPage bool, workerCh chan struct{}, done chan bool, errCh chan error, wg *sync.WaitGroup) {
 	log.Println("start get", url)
-	r := requests.GET(url, requests.WithCookie(cookie))
-	if r.StatusCode() != http.StatusOK {
-		err := fmt.Errorf("http GET status error: [%v]%v", r.StatusCode(), r.StatusText())
-		log.Fatalln(err)
-	}
-	m := r.Map()
-	data := m["data"].([]any)
-	if len(data) == 0 {
+	m, err := client.Get(url)
+	if err != nil {
+		errCh <- err
+		<-workerCh
+		return
+	}
+	items, err := src.Items(m)
+	if err != nil {
+		errCh <- err
+		<-workerCh
+		return
+	}
+	if len(items) == 0 {
+		// A first page that comes back empty is the other shape Weibo
+		// uses for an expired cookie (see weibo.looksLoggedOut for the
+		// "ok"/"msg" shape), but only when looksLikeCookieExpiry can
+		// tell that apart from a legitimate empty result.
+		if firstPage && looksLikeCookieExpiry(src, ss) {
+			errCh <- weiboclient.ErrCookieExpired
+			<-workerCh
+			return
+		}
 		done <- true
+		<-workerCh
+		return
+	}
+
+	ids := make([]string, 0, len(items))
+	for _, d := range items {
+		if id, ok := d["idstr"].(string); ok {
+			ids = append(ids, id)
+		}
 	}
-	for _, d := range data {
-		dd := d.(map[string]any)
+	if ss.seenAll(ids) {
+		log.Println("caught up with last sync, stopping")
+		done <- true
+		<-workerCh
+		return
+	}
+
+	for _, d := range items {
 		wg.Add(1)
-		weiboChan <- parseWeibo(dd)
+		weiboChan <- model.Parse(d, client)
 	}
 
 	<-workerCh
 }
 
-type weibo struct {
-	id         string
-	isLongText bool // “查看更多”
-	text       string
-	links      []string // “网页链接”
-}
-
-func parseWeibo(d map[string]any) weibo {
-	weibo := weibo{}
-	weibo.id = d["idstr"].(string)
-	if _, ok := d["isLongText"]; ok {
-		weibo.isLongText = d["isLongText"].(bool)
-	}
-	if _, ok := d["text"]; ok {
-		weibo.text = d["text"].(string)
-	} else {
-		weibo.text = "no text"
+// toRecord converts a model.Weibo into the storage-agnostic shape that
+// storage.Store and export.Exporter work with.
+func toRecord(sourceName string, w model.Weibo, fetchedAt time.Time) storage.Record {
+	raw, err := json.Marshal(w.Raw)
+	if err != nil {
+		raw = nil
 	}
-	if _, ok := d["url_struct"]; ok {
-		url_struct := d["url_struct"].([]any)
-		for _, u := range url_struct {
-			uu := u.(map[string]any)
-			weibo.links = append(weibo.links, uu["long_url"].(string))
-		}
+	return storage.Record{
+		Source:     sourceName,
+		ID:         w.ID,
+		Text:       w.Text,
+		IsLongText: w.IsLongText,
+		Links:      w.Links,
+		CreatedAt:  w.CreatedAt,
+		FetchedAt:  fetchedAt,
+		Raw:        raw,
 	}
-	return weibo
 }
 
 var rootCmd = &cobra.Command{
 	Use:     "get-weibo-favorites",
 	Example: "  get-weibo-favorites -c <your-weibo-cookie>",
-	Short:   "A command-line tool to crawl Weibo favorites",
-	Long:    `A command-line tool to crawl Weibo favorites and save them to a CSV file.`,
-	PreRun: func(cmd *cobra.Command, args []string) {
+	Short:   "A command-line tool to crawl Weibo favorites, timelines, statuses, and search results",
+	Long:    `A command-line tool to crawl Weibo and save it to a CSV, JSONL, or SQLite store, or export it to Telegram/a webhook.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		cookie, _ := cmd.Flags().GetString("cookie")
 		if cookie == "" {
 			log.Println("cookie is required")
@@ -106,46 +202,161 @@ var rootCmd = &cobra.Command{
 			os.Exit(1)
 		}
 	},
-	Run: func(cmd *cobra.Command, args []string) {
-		cookie, _ := cmd.Flags().GetString("cookie")
-		pageNumber, _ := cmd.Flags().GetInt("page")
+	// Run defaults to crawling favorites, preserved for users invoking
+	// the binary without a subcommand; `fav` is the same behavior.
+	Run: runFavCrawl,
+}
 
-		f, err := createCSV()
+// buildFormatExporter returns the extra exporter requested by --format,
+// on top of the SQLite store runCrawl always keeps for incremental
+// sync bookkeeping. It backs every source subcommand (fav/user/status/
+// search), so csv, jsonl, telegram, and webhook are all reachable from
+// any source; the dedicated `export` subcommands build their exporter
+// themselves.
+func buildFormatExporter(cmd *cobra.Command, format string) []export.Exporter {
+	switch format {
+	case "sqlite":
+		return nil
+	case "csv":
+		e, err := export.NewCSVExporter(outputFileName("csv"))
+		if err != nil {
+			log.Fatalln(err)
+		}
+		return []export.Exporter{e}
+	case "jsonl":
+		e, err := export.NewJSONLExporter(outputFileName("jsonl"))
+		if err != nil {
+			log.Fatalln(err)
+		}
+		return []export.Exporter{e}
+	case "telegram":
+		if err := requireFlags(cmd, "bot-token", "chat-id"); err != nil {
+			log.Fatalln(err)
+		}
+		token, _ := cmd.Flags().GetString("bot-token")
+		chatID, _ := cmd.Flags().GetString("chat-id")
+		tmpl, _ := cmd.Flags().GetString("template")
+		e, err := export.NewTelegramExporter(token, chatID, tmpl)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		return []export.Exporter{e}
+	case "webhook":
+		if err := requireFlags(cmd, "url"); err != nil {
+			log.Fatalln(err)
+		}
+		url, _ := cmd.Flags().GetString("url")
+		tmpl, _ := cmd.Flags().GetString("template")
+		e, err := export.NewWebhookExporter(url, tmpl)
 		if err != nil {
 			log.Fatalln(err)
 		}
-		defer f.Close()
-		wg := new(sync.WaitGroup)
-		go func() {
-			for w := range weiboChan {
-				_, err := f.WriteString(fmt.Sprintf("%s\t%s\t%t\t%s\n", w.id, w.text, w.isLongText, strings.Join(w.links, " , ")))
-				if err != nil {
-					log.Fatalln(err)
+		return []export.Exporter{e}
+	default:
+		log.Fatalf("unknown --format %q, want one of csv|sqlite|jsonl|telegram|webhook", format)
+		return nil
+	}
+}
+
+// runCrawl wires up the client, store, and archiver shared by every
+// command, fans out each crawled weibo to extra plus the store, and
+// blocks until the crawl finishes.
+func runCrawl(cmd *cobra.Command, src source.Source, extra []export.Exporter) error {
+	cookie, _ := cmd.Flags().GetString("cookie")
+	pageNumber, _ := cmd.Flags().GetInt("page")
+	full, _ := cmd.Flags().GetBool("full")
+	download, _ := cmd.Flags().GetString("download")
+	qps, _ := cmd.Flags().GetFloat64("qps")
+	span, _ := cmd.Flags().GetDuration("span")
+
+	client := weiboclient.NewClient(cookie)
+	client.QPS = qps
+	client.Span = span
+
+	var arch *archiver.Archiver
+	if download != "" {
+		kinds, err := parseDownloadKinds(download)
+		if err != nil {
+			return err
+		}
+		arch = archiver.New("archive", kinds, workerNumber)
+	}
+
+	store, err := sqlite.Open("weiboFavorites.db")
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	fanOut := &export.FanOut{Exporters: append(extra, export.NewStoreExporter(store))}
+	defer fanOut.Close()
+
+	wg := new(sync.WaitGroup)
+	go func() {
+		for w := range weiboChan {
+			fetchedAt := time.Now()
+			fanOut.Export(toRecord(src.Name(), w, fetchedAt))
+			if arch != nil {
+				if err := arch.Archive(w.ID, w.Raw); err != nil {
+					log.Println("archiver:", err)
 				}
-				wg.Done()
 			}
-		}()
-		defer close(weiboChan)
-		getWeiboFav(cookie, pageNumber, wg)
-		wg.Wait()
-	},
+			wg.Done()
+		}
+	}()
+	defer close(weiboChan)
+	if err := crawl(src, client, pageNumber, &syncState{store: store, full: full, source: src.Name()}, wg); err != nil {
+		if errors.Is(err, weiboclient.ErrCookieExpired) {
+			log.Println(err)
+			log.Println("please refresh --cookie and re-run")
+			os.Exit(1)
+		}
+		return err
+	}
+	wg.Wait()
+	return nil
 }
 
-func createCSV() (*os.File, error) {
-	startTime := time.Now().Format("2006-01-02-15:04")
-	fileName := fmt.Sprintf("weiboFavorites-%s.csv", startTime)
-	f, err := os.OpenFile(fileName, os.O_CREATE|os.O_RDWR, 0777)
-	if err != nil {
-		return nil, err
+// parseDownloadKinds turns a --download value like "images,videos" into
+// the archiver.Kind list Archiver expects.
+func parseDownloadKinds(value string) ([]archiver.Kind, error) {
+	var kinds []archiver.Kind
+	for _, k := range strings.Split(value, ",") {
+		switch archiver.Kind(k) {
+		case archiver.KindImage, archiver.KindVideo, archiver.KindPage:
+			kinds = append(kinds, archiver.Kind(k))
+		default:
+			return nil, fmt.Errorf("unknown --download kind %q, want images|videos|pages", k)
+		}
 	}
-	return f, nil
+	return kinds, nil
+}
+
+// outputFileName builds a timestamped output file name such as
+// weiboFavorites-2006-01-02-15:04.csv, matching the original CSV
+// exporter's naming.
+func outputFileName(ext string) string {
+	startTime := time.Now().Format("2006-01-02-15:04")
+	return fmt.Sprintf("weiboFavorites-%s.%s", startTime, ext)
 }
 
 func init() {
-	rootCmd.Flags().StringP("cookie", "c", "", "your Weibo cookie")
-	rootCmd.MarkFlagRequired("cookie")
-	rootCmd.Flags().IntP("page", "p", 0, "the page number to end at. If you don't specify a page number, it will crawl all pages.")
+	rootCmd.PersistentFlags().StringP("cookie", "c", "", "your Weibo cookie")
+	rootCmd.MarkPersistentFlagRequired("cookie")
+	rootCmd.PersistentFlags().IntP("page", "p", 0, "the page number to end at. If you don't specify a page number, it will crawl all pages.")
+	rootCmd.PersistentFlags().Bool("full", false, "re-crawl from page 1 instead of stopping at the last synced weibo")
+	rootCmd.PersistentFlags().String("download", "", "comma-separated artifact kinds to archive under archive/<weibo_id>/: images,videos,pages")
+	rootCmd.PersistentFlags().Float64("qps", 0, "maximum requests per second to Weibo (0 = unlimited)")
+	rootCmd.PersistentFlags().Duration("span", 0, "minimum delay between requests to Weibo, e.g. 500ms (overrides --qps)")
+	rootCmd.PersistentFlags().String("format", "sqlite", "output format: csv|sqlite|jsonl|telegram|webhook")
 
+	// Only consulted when --format is telegram or webhook, but kept
+	// persistent so every source subcommand (fav/user/status/search)
+	// and the dedicated `export` subcommands share one set of flags.
+	rootCmd.PersistentFlags().String("bot-token", "", "Telegram bot token (--format=telegram)")
+	rootCmd.PersistentFlags().String("chat-id", "", "Telegram chat id to post to (--format=telegram)")
+	rootCmd.PersistentFlags().String("url", "", "webhook URL to POST each weibo to (--format=webhook)")
+	rootCmd.PersistentFlags().String("template", "", "Go text/template for the message/request body (default: built-in template)")
 }
 
 func main() {
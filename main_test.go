@@ -0,0 +1,133 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xujiajiadexiaokeai/get-weibo-favorites/storage"
+	weiboclient "github.com/xujiajiadexiaokeai/get-weibo-favorites/weibo"
+)
+
+// fakeSeenSource is a non-paginated source whose single page is already
+// fully present in the store, exercising crawl's "caught up" path.
+type fakeSeenSource struct {
+	url string
+}
+
+func (s fakeSeenSource) Name() string       { return "fake" }
+func (s fakeSeenSource) Paginated() bool    { return false }
+func (s fakeSeenSource) PageURL(int) string { return s.url }
+func (s fakeSeenSource) Items(resp map[string]any) ([]map[string]any, error) {
+	return []map[string]any{{"idstr": "123"}}, nil
+}
+
+// fakeSeenStore implements storage.Store with Has always reporting the
+// id as already synced for any source.
+type fakeSeenStore struct{}
+
+func (f fakeSeenStore) Save(records []storage.Record) error      { return nil }
+func (f fakeSeenStore) Has(source, id string) (bool, error)      { return true, nil }
+func (f fakeSeenStore) SourceSynced(source string) (bool, error) { return false, nil }
+func (f fakeSeenStore) Close() error                             { return nil }
+
+// fakeStore implements storage.Store with canned, configurable answers.
+type fakeStore struct {
+	sourceSynced bool
+}
+
+func (f fakeStore) Save(records []storage.Record) error      { return nil }
+func (f fakeStore) Has(source, id string) (bool, error)      { return false, nil }
+func (f fakeStore) SourceSynced(source string) (bool, error) { return f.sourceSynced, nil }
+func (f fakeStore) Close() error                             { return nil }
+
+func TestCrawl_NonPaginatedCaughtUpDoesNotDeadlock(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":[{"idstr":"123"}]}`))
+	}))
+	defer srv.Close()
+
+	client := weiboclient.NewClient("fake-cookie")
+	ss := &syncState{store: fakeSeenStore{}}
+	wg := new(sync.WaitGroup)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- crawl(fakeSeenSource{url: srv.URL}, client, 0, ss, wg)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("crawl: %v", err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("crawl deadlocked on a re-fetch of an already-synced non-paginated source")
+	}
+}
+
+// fakeEmptyFirstPageSource's single page always comes back with no
+// items, mimicking either an expired cookie (for favorites, once a
+// previous sync proved the account has some) or a legitimate empty
+// result (a fresh account, or any non-favorites source).
+type fakeEmptyFirstPageSource struct {
+	url  string
+	name string
+}
+
+func (s fakeEmptyFirstPageSource) Name() string       { return s.name }
+func (s fakeEmptyFirstPageSource) Paginated() bool    { return false }
+func (s fakeEmptyFirstPageSource) PageURL(int) string { return s.url }
+func (s fakeEmptyFirstPageSource) Items(resp map[string]any) ([]map[string]any, error) {
+	return nil, nil
+}
+
+func emptyDataServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":[]}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestCrawl_EmptyFirstPageLooksLikeCookieExpired_FavoritesPreviouslySynced(t *testing.T) {
+	srv := emptyDataServer(t)
+	client := weiboclient.NewClient("fake-cookie")
+	ss := &syncState{store: fakeStore{sourceSynced: true}, source: "favorites"}
+	wg := new(sync.WaitGroup)
+
+	err := crawl(fakeEmptyFirstPageSource{url: srv.URL, name: "favorites"}, client, 0, ss, wg)
+	if !errors.Is(err, weiboclient.ErrCookieExpired) {
+		t.Fatalf("crawl = %v, want ErrCookieExpired", err)
+	}
+}
+
+func TestCrawl_EmptyFirstPageIsNotCookieExpired_FreshFavoritesAccount(t *testing.T) {
+	srv := emptyDataServer(t)
+	client := weiboclient.NewClient("fake-cookie")
+	ss := &syncState{store: fakeStore{sourceSynced: false}, source: "favorites"}
+	wg := new(sync.WaitGroup)
+
+	err := crawl(fakeEmptyFirstPageSource{url: srv.URL, name: "favorites"}, client, 0, ss, wg)
+	if err != nil {
+		t.Fatalf("crawl = %v, want nil (a brand new account has zero favorites legitimately)", err)
+	}
+}
+
+func TestCrawl_EmptyFirstPageIsNotCookieExpired_NonFavoritesSource(t *testing.T) {
+	srv := emptyDataServer(t)
+	client := weiboclient.NewClient("fake-cookie")
+	ss := &syncState{store: fakeStore{sourceSynced: true}, source: "search"}
+	wg := new(sync.WaitGroup)
+
+	err := crawl(fakeEmptyFirstPageSource{url: srv.URL, name: "search"}, client, 0, ss, wg)
+	if err != nil {
+		t.Fatalf("crawl = %v, want nil (a zero-hit search is a legitimate empty result)", err)
+	}
+}
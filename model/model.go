@@ -0,0 +1,99 @@
+// Package model holds the weibo shape shared by every source (favorites,
+// a user's timeline, a single status, search) and every exporter.
+package model
+
+import (
+	"log"
+	"time"
+
+	weiboclient "github.com/xujiajiadexiaokeai/get-weibo-favorites/weibo"
+)
+
+// User is a weibo's author, as returned by Weibo's ajax endpoints.
+type User struct {
+	ID         string
+	ScreenName string
+}
+
+// Weibo is a single crawled weibo, normalized from Weibo's ajax JSON
+// shape regardless of which source it came from.
+type Weibo struct {
+	ID         string
+	MblogID    string
+	IsLongText bool // “查看更多”
+	Text       string
+	Links      []string // “网页链接”
+	User       *User
+	CreatedAt  time.Time
+
+	RepostsCount   int
+	CommentsCount  int
+	AttitudesCount int
+
+	// Retweeted is set when this weibo is a repost of another.
+	Retweeted *Weibo
+
+	Raw map[string]any
+}
+
+// Parse converts a raw weibo JSON object, as returned inline by any of
+// the ajax endpoints, into a Weibo. It follows up on isLongText entries
+// via client (which may be nil to skip that step, e.g. in tests) and
+// recurses into retweeted_status.
+func Parse(d map[string]any, client *weiboclient.Client) Weibo {
+	w := Weibo{Raw: d}
+	w.ID, _ = d["idstr"].(string)
+	w.MblogID, _ = d["mblogid"].(string)
+	if v, ok := d["isLongText"]; ok {
+		w.IsLongText, _ = v.(bool)
+	}
+	if v, ok := d["text"]; ok {
+		w.Text, _ = v.(string)
+	} else {
+		w.Text = "no text"
+	}
+	if w.IsLongText && w.MblogID != "" && client != nil {
+		if fullText, err := client.FetchLongText(w.MblogID); err != nil {
+			log.Println("model: FetchLongText:", err)
+		} else {
+			w.Text = fullText
+		}
+	}
+	if urlStruct, ok := d["url_struct"].([]any); ok {
+		for _, u := range urlStruct {
+			if uu, ok := u.(map[string]any); ok {
+				if link, ok := uu["long_url"].(string); ok {
+					w.Links = append(w.Links, link)
+				}
+			}
+		}
+	}
+	if u, ok := d["user"].(map[string]any); ok {
+		user := User{}
+		user.ID, _ = u["idstr"].(string)
+		user.ScreenName, _ = u["screen_name"].(string)
+		w.User = &user
+	}
+	if s, ok := d["created_at"].(string); ok {
+		// Weibo's created_at, e.g. "Mon Jan 02 15:04:05 +0800 2006".
+		if t, err := time.Parse(time.RubyDate, s); err == nil {
+			w.CreatedAt = t
+		}
+	}
+	w.RepostsCount = intField(d, "reposts_count")
+	w.CommentsCount = intField(d, "comments_count")
+	w.AttitudesCount = intField(d, "attitudes_count")
+	if rt, ok := d["retweeted_status"].(map[string]any); ok {
+		retweeted := Parse(rt, client)
+		w.Retweeted = &retweeted
+	}
+	return w
+}
+
+func intField(d map[string]any, key string) int {
+	v, ok := d[key].(float64)
+	if !ok {
+		return 0
+	}
+	return int(v)
+}
@@ -0,0 +1,17 @@
+package source
+
+import "fmt"
+
+// Favorites crawls the signed-in user's favorited weibo.
+type Favorites struct{}
+
+func (Favorites) Name() string    { return "favorites" }
+func (Favorites) Paginated() bool { return true }
+
+func (Favorites) PageURL(page int) string {
+	return fmt.Sprintf("https://weibo.com/ajax/favorites/all_fav?page=%d", page)
+}
+
+func (Favorites) Items(resp map[string]any) ([]map[string]any, error) {
+	return dataList(resp)
+}
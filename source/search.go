@@ -0,0 +1,22 @@
+package source
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// Search crawls Weibo's side search for a query.
+type Search struct {
+	Query string
+}
+
+func (s Search) Name() string    { return "search" }
+func (s Search) Paginated() bool { return true }
+
+func (s Search) PageURL(page int) string {
+	return fmt.Sprintf("https://weibo.com/ajax/side/search?q=%s&page=%d", url.QueryEscape(s.Query), page)
+}
+
+func (Search) Items(resp map[string]any) ([]map[string]any, error) {
+	return dataList(resp)
+}
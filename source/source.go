@@ -0,0 +1,40 @@
+// Package source abstracts where a crawl's weibo entries come from:
+// the favorites feed, a user's timeline, a single status, or a search,
+// so the crawler core can page through any of them the same way.
+package source
+
+import "fmt"
+
+// Source describes one Weibo ajax endpoint to crawl.
+type Source interface {
+	Name() string
+
+	// Paginated reports whether PageURL should be called with
+	// increasing page numbers. Single-result sources like Status
+	// return false and are fetched exactly once.
+	Paginated() bool
+
+	// PageURL returns the endpoint URL for the given page. page is
+	// ignored when Paginated returns false.
+	PageURL(page int) string
+
+	// Items extracts the raw weibo entries from a decoded response
+	// body.
+	Items(resp map[string]any) ([]map[string]any, error)
+}
+
+// dataList reads the "data": [...] shape shared by the favorites,
+// timeline, and search endpoints.
+func dataList(resp map[string]any) ([]map[string]any, error) {
+	raw, ok := resp["data"].([]any)
+	if !ok {
+		return nil, fmt.Errorf("source: response missing data list")
+	}
+	items := make([]map[string]any, 0, len(raw))
+	for _, r := range raw {
+		if m, ok := r.(map[string]any); ok {
+			items = append(items, m)
+		}
+	}
+	return items, nil
+}
@@ -0,0 +1,28 @@
+package source
+
+import "fmt"
+
+// Status crawls a single weibo by its mblogid. It is not paginated:
+// PageURL ignores its argument and Items wraps the single result.
+type Status struct {
+	MblogID string
+}
+
+func (s Status) Name() string   { return "status" }
+func (s Status) Paginated() bool { return false }
+func (s Status) PageURL(int) string {
+	return fmt.Sprintf("https://weibo.com/ajax/statuses/show?id=%s", s.MblogID)
+}
+
+func (Status) Items(resp map[string]any) ([]map[string]any, error) {
+	d, ok := resp["data"].(map[string]any)
+	if !ok {
+		// Some deployments return the weibo as the top-level object
+		// rather than nested under "data".
+		if _, hasID := resp["idstr"]; hasID {
+			return []map[string]any{resp}, nil
+		}
+		return nil, fmt.Errorf("source: status response missing data")
+	}
+	return []map[string]any{d}, nil
+}
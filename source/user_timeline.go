@@ -0,0 +1,19 @@
+package source
+
+import "fmt"
+
+// UserTimeline crawls a single user's own posts.
+type UserTimeline struct {
+	UID string
+}
+
+func (s UserTimeline) Name() string    { return "user_timeline" }
+func (s UserTimeline) Paginated() bool { return true }
+
+func (s UserTimeline) PageURL(page int) string {
+	return fmt.Sprintf("https://weibo.com/ajax/statuses/mymblog?uid=%s&page=%d", s.UID, page)
+}
+
+func (UserTimeline) Items(resp map[string]any) ([]map[string]any, error) {
+	return dataList(resp)
+}
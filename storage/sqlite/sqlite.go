@@ -0,0 +1,122 @@
+// Package sqlite implements storage.Store on top of a single-file SQLite
+// database, used as the default durable store for get-weibo-favorites.
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strings"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/xujiajiadexiaokeai/get-weibo-favorites/storage"
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS weibo (
+	source      TEXT NOT NULL,
+	id          TEXT NOT NULL,
+	text        TEXT NOT NULL,
+	is_long_text INTEGER NOT NULL,
+	links       TEXT NOT NULL,
+	created_at  DATETIME,
+	fetched_at  DATETIME NOT NULL,
+	raw         TEXT NOT NULL,
+	PRIMARY KEY (source, id)
+);
+CREATE INDEX IF NOT EXISTS idx_weibo_fetched_at ON weibo (fetched_at);
+`
+
+// Store is a storage.Store backed by a SQLite database file.
+type Store struct {
+	db *sql.DB
+}
+
+// Open opens (and migrates, if needed) the SQLite database at path.
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Save(records []storage.Record) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare(`
+		INSERT INTO weibo (source, id, text, is_long_text, links, created_at, fetched_at, raw)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(source, id) DO UPDATE SET
+			text = excluded.text,
+			is_long_text = excluded.is_long_text,
+			links = excluded.links,
+			created_at = excluded.created_at,
+			fetched_at = excluded.fetched_at,
+			raw = excluded.raw
+	`)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+
+	for _, r := range records {
+		if _, err := stmt.Exec(
+			r.Source,
+			r.ID,
+			r.Text,
+			r.IsLongText,
+			strings.Join(r.Links, ","),
+			r.CreatedAt,
+			r.FetchedAt,
+			string(mustMarshal(r.Raw)),
+		); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// mustMarshal passes raw through unchanged, re-encoding only if it isn't
+// already valid JSON (e.g. nil), so a missing payload doesn't fail the
+// whole batch.
+func mustMarshal(raw []byte) []byte {
+	if json.Valid(raw) {
+		return raw
+	}
+	b, err := json.Marshal(string(raw))
+	if err != nil {
+		return []byte("null")
+	}
+	return b
+}
+
+func (s *Store) Has(source, id string) (bool, error) {
+	var n int
+	err := s.db.QueryRow(`SELECT COUNT(1) FROM weibo WHERE source = ? AND id = ?`, source, id).Scan(&n)
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (s *Store) SourceSynced(source string) (bool, error) {
+	var n int
+	err := s.db.QueryRow(`SELECT COUNT(1) FROM weibo WHERE source = ? LIMIT 1`, source).Scan(&n)
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,53 @@
+package sqlite
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/xujiajiadexiaokeai/get-weibo-favorites/storage"
+)
+
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	s, err := Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestStore_HasIsPerSource(t *testing.T) {
+	s := openTestStore(t)
+
+	if err := s.Save([]storage.Record{{Source: "favorites", ID: "123", FetchedAt: time.Now()}}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	if ok, err := s.Has("favorites", "123"); err != nil || !ok {
+		t.Errorf("Has(favorites, 123) = %v, %v, want true, nil", ok, err)
+	}
+	if ok, err := s.Has("search", "123"); err != nil || ok {
+		t.Errorf("Has(search, 123) = %v, %v, want false, nil: an id saved from favorites shouldn't count as synced for search", ok, err)
+	}
+}
+
+func TestStore_SourceSynced(t *testing.T) {
+	s := openTestStore(t)
+
+	if ok, err := s.SourceSynced("favorites"); err != nil || ok {
+		t.Errorf("SourceSynced(favorites) on empty store = %v, %v, want false, nil", ok, err)
+	}
+
+	if err := s.Save([]storage.Record{{Source: "favorites", ID: "1", FetchedAt: time.Now()}}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	if ok, err := s.SourceSynced("favorites"); err != nil || !ok {
+		t.Errorf("SourceSynced(favorites) = %v, %v, want true, nil", ok, err)
+	}
+	if ok, err := s.SourceSynced("search"); err != nil || ok {
+		t.Errorf("SourceSynced(search) = %v, %v, want false, nil", ok, err)
+	}
+}
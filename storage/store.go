@@ -0,0 +1,46 @@
+// Package storage defines the persistence layer used to keep track of
+// crawled weibo favorites across runs.
+package storage
+
+import (
+	"time"
+)
+
+// Record is one persisted weibo, independent of the Weibo API shape so
+// that storage does not need to know about feed parsing details.
+type Record struct {
+	// Source is the source.Source.Name() that fetched this record
+	// (e.g. "favorites", "search"), since the same weibo id can be
+	// reachable from more than one source.
+	Source     string
+	ID         string
+	Text       string
+	IsLongText bool
+	Links      []string
+	CreatedAt  time.Time
+	FetchedAt  time.Time
+	Raw        []byte // raw JSON payload as returned by the Weibo API
+}
+
+// Store persists weibo records and lets the crawler resume an
+// incremental sync instead of re-crawling from page 1 every run.
+type Store interface {
+	// Save upserts a batch of records, keyed by (Record.Source, Record.ID).
+	Save(records []Record) error
+
+	// Has reports whether a record with the given id has already been
+	// saved for source, used to stop paging once the crawler catches
+	// up with that source's last sync. The same id saved under a
+	// different source doesn't count: a search result that happens to
+	// match an id already saved from favorites hasn't itself been
+	// synced yet.
+	Has(source, id string) (bool, error)
+
+	// SourceSynced reports whether any record has ever been saved for
+	// source, used to tell a genuinely empty result (e.g. a zero-hit
+	// search, a brand new favorites account) apart from a result that
+	// looks empty because something went wrong.
+	SourceSynced(source string) (bool, error)
+
+	Close() error
+}
@@ -0,0 +1,131 @@
+// Package weibo is a thin client over Weibo's ajax endpoints, shared by
+// the crawler and the model package's long-text follow-up. The archiver
+// does not use it: it downloads artifacts with plain HTTP GETs.
+package weibo
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/youseebiggirl/requests"
+)
+
+const defaultBaseURL = "https://weibo.com"
+
+// defaultMaxRetries bounds how many times Get retries a request that
+// comes back throttled or briefly unavailable, before giving up.
+const defaultMaxRetries = 3
+
+// Client wraps requests with the cookie needed to authenticate against
+// Weibo's ajax endpoints, plus the rate limiting and retry behavior
+// every endpoint needs to avoid tripping Weibo's anti-crawl defenses.
+type Client struct {
+	Cookie string
+
+	// QPS throttles requests to at most this many per second. Span, if
+	// set, takes precedence and fixes the spacing directly, mirroring
+	// the older backup-weibo gist's -span option.
+	QPS  float64
+	Span time.Duration
+
+	// MaxRetries bounds retries of 418/429/5xx responses. Zero means
+	// defaultMaxRetries.
+	MaxRetries int
+
+	// baseURL overrides defaultBaseURL in tests.
+	baseURL string
+
+	throttle throttle
+}
+
+// NewClient returns a Client that authenticates with the given cookie.
+func NewClient(cookie string) *Client {
+	return &Client{Cookie: cookie}
+}
+
+// Get issues a rate-limited, retried GET against url and returns the
+// decoded JSON body. It returns ErrCookieExpired if the response has
+// the "login required" shape Weibo returns once a cookie has expired.
+func (c *Client) Get(url string) (map[string]any, error) {
+	c.throttle.interval = c.interval()
+
+	maxRetries := c.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = defaultMaxRetries
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		c.throttle.wait()
+
+		r := requests.GET(url, requests.WithCookie(c.Cookie))
+		status := r.StatusCode()
+		if status == http.StatusOK {
+			m := r.Map()
+			if looksLoggedOut(m) {
+				return nil, ErrCookieExpired
+			}
+			return m, nil
+		}
+
+		lastErr = fmt.Errorf("weibo: GET %s: status error: [%v]%v", url, status, r.StatusText())
+		if !retryable(status) || attempt == maxRetries {
+			return nil, lastErr
+		}
+		time.Sleep(backoff(attempt))
+	}
+	return nil, lastErr
+}
+
+// retryable reports whether status is worth retrying: Weibo's own
+// throttle response (418), a generic rate limit (429), or a transient
+// server error (5xx).
+func retryable(status int) bool {
+	return status == 418 || status == http.StatusTooManyRequests || status >= 500
+}
+
+// backoff returns an exponentially increasing delay with jitter for the
+// given (zero-based) retry attempt.
+func backoff(attempt int) time.Duration {
+	base := time.Second * time.Duration(1<<attempt)
+	jitter := time.Duration(rand.Int63n(int64(base) / 2))
+	return base + jitter
+}
+
+func (c *Client) interval() time.Duration {
+	if c.Span > 0 {
+		return c.Span
+	}
+	if c.QPS > 0 {
+		return time.Duration(float64(time.Second) / c.QPS)
+	}
+	return 0
+}
+
+// FetchLongText fetches the full text of a weibo whose feed entry was
+// truncated (isLongText == true), identified by its mblogid.
+func (c *Client) FetchLongText(mblogID string) (string, error) {
+	url := fmt.Sprintf("%s/ajax/statuses/longtext?id=%s", c.base(), mblogID)
+	m, err := c.Get(url)
+	if err != nil {
+		return "", err
+	}
+	data, ok := m["data"].(map[string]any)
+	if !ok {
+		return "", fmt.Errorf("weibo: longtext response missing data for id %s", mblogID)
+	}
+	text, ok := data["longTextContent"].(string)
+	if !ok {
+		return "", fmt.Errorf("weibo: longtext response missing longTextContent for id %s", mblogID)
+	}
+	return text, nil
+}
+
+func (c *Client) base() string {
+	if c.baseURL != "" {
+		return c.baseURL
+	}
+	return defaultBaseURL
+}
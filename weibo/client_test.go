@@ -0,0 +1,45 @@
+package weibo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func serveFixture(t *testing.T, path string) *httptest.Server {
+	t.Helper()
+	body, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}))
+}
+
+func TestClient_FetchLongText(t *testing.T) {
+	srv := serveFixture(t, "testdata/longtext.json")
+	defer srv.Close()
+
+	c := &Client{Cookie: "fake-cookie", baseURL: srv.URL}
+	text, err := c.FetchLongText("mblog123")
+	if err != nil {
+		t.Fatalf("FetchLongText: %v", err)
+	}
+	const want = "This is the full, untruncated weibo text that only the longtext endpoint returns."
+	if text != want {
+		t.Errorf("FetchLongText = %q, want %q", text, want)
+	}
+}
+
+func TestClient_FetchLongText_missingContent(t *testing.T) {
+	srv := serveFixture(t, "testdata/longtext_missing.json")
+	defer srv.Close()
+
+	c := &Client{Cookie: "fake-cookie", baseURL: srv.URL}
+	if _, err := c.FetchLongText("mblog123"); err == nil {
+		t.Fatal("FetchLongText: expected error for missing longTextContent, got nil")
+	}
+}
@@ -0,0 +1,24 @@
+package weibo
+
+import "errors"
+
+// ErrCookieExpired is returned when a response has the "login required"
+// shape Weibo returns once a cookie has expired, instead of crawling on
+// and eventually failing on malformed data.
+var ErrCookieExpired = errors.New("weibo: cookie expired or invalid, please refresh it")
+
+// looksLoggedOut reports whether a decoded JSON response matches the
+// shape Weibo returns when the cookie is no longer valid: a negative
+// "ok" field accompanied by a "msg" explaining why.
+func looksLoggedOut(m map[string]any) bool {
+	ok, exists := m["ok"]
+	if !exists {
+		return false
+	}
+	n, isNum := ok.(float64)
+	if !isNum || n >= 0 {
+		return false
+	}
+	msg, _ := m["msg"].(string)
+	return msg != ""
+}
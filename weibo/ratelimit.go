@@ -0,0 +1,29 @@
+package weibo
+
+import (
+	"sync"
+	"time"
+)
+
+// throttle is a minimal token-bucket-of-one rate limiter: it never lets
+// two requests start closer together than interval, which is enough to
+// keep a single crawler under Weibo's anti-crawl thresholds.
+type throttle struct {
+	mu       sync.Mutex
+	interval time.Duration
+	last     time.Time
+}
+
+func (t *throttle) wait() {
+	if t == nil || t.interval <= 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.last.IsZero() {
+		if remaining := t.interval - time.Since(t.last); remaining > 0 {
+			time.Sleep(remaining)
+		}
+	}
+	t.last = time.Now()
+}